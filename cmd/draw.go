@@ -0,0 +1,20 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/nektos/act/pkg/model"
+)
+
+// drawGraph prints the jobs a plan would run without running them, for `--list`. Jobs expanded
+// from a `strategy.matrix` are printed once per combination so the fan-out is visible.
+func drawGraph(plan *model.Plan) error {
+	for _, run := range plan.Runs {
+		if len(run.Matrix) == 0 {
+			fmt.Println(run.JobID)
+			continue
+		}
+		fmt.Printf("%s (%s)\n", run.JobID, run.Matrix.String())
+	}
+	return nil
+}
@@ -2,12 +2,15 @@ package cmd
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/nektos/act/pkg/common"
 
-	fswatch "github.com/andreaskoch/go-fswatch"
+	"github.com/fsnotify/fsnotify"
 	"github.com/nektos/act/pkg/model"
 	"github.com/nektos/act/pkg/runner"
 	gitignore "github.com/sabhiram/go-gitignore"
@@ -28,8 +31,10 @@ func Execute(ctx context.Context, version string) {
 		SilenceUsage:     true,
 	}
 	rootCmd.Flags().BoolP("watch", "w", false, "watch the contents of the local repo and run when files change")
+	rootCmd.Flags().Duration("watch-debounce", 500*time.Millisecond, "coalesce bursts of filesystem changes within this window before re-running in --watch mode")
 	rootCmd.Flags().BoolP("list", "l", false, "list workflows")
 	rootCmd.Flags().StringP("job", "j", "", "run job")
+	rootCmd.Flags().StringArrayP("matrix", "", []string{}, "specify matrix values to run, e.g. --matrix os=ubuntu-latest --matrix node=14")
 	rootCmd.Flags().BoolVarP(&input.reuseContainers, "reuse", "r", false, "reuse action containers to maintain state")
 	rootCmd.Flags().BoolVarP(&input.forcePull, "pull", "p", false, "pull docker image(s) if already present")
 	rootCmd.Flags().StringVarP(&input.eventPath, "event", "e", "", "path to event JSON file")
@@ -38,6 +43,8 @@ func Execute(ctx context.Context, version string) {
 	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "verbose output")
 	rootCmd.PersistentFlags().BoolVarP(&input.logOutput, "output", "o", false, "log output from steps")
 	rootCmd.PersistentFlags().BoolVarP(&input.dryrun, "dryrun", "n", false, "dryrun mode")
+	rootCmd.PersistentFlags().String("uses-cache", filepath.Join(os.TempDir(), "act", "uses-cache"), "directory to cache remote `uses:` workflow references in")
+	rootCmd.AddCommand(serverCommand(ctx, input))
 	if err := rootCmd.Execute(); err != nil {
 		os.Exit(1)
 	}
@@ -53,7 +60,12 @@ func setupLogging(cmd *cobra.Command, args []string) {
 
 func newRunCommand(ctx context.Context, input *Input) func(*cobra.Command, []string) error {
 	return func(cmd *cobra.Command, args []string) error {
-		planner, err := model.NewWorkflowPlanner(input.WorkflowsPath())
+		usesCacheDir, err := cmd.Flags().GetString("uses-cache")
+		if err != nil {
+			return err
+		}
+
+		planner, err := model.NewWorkflowPlanner(input.WorkflowsPath(), input.Workdir(), usesCacheDir)
 		if err != nil {
 			return err
 		}
@@ -69,17 +81,33 @@ func newRunCommand(ctx context.Context, input *Input) func(*cobra.Command, []str
 			eventName = events[0]
 		}
 
+		matrixFilters, err := cmd.Flags().GetStringArray("matrix")
+		if err != nil {
+			return err
+		}
+		matrix, err := parseMatrixFilters(matrixFilters)
+		if err != nil {
+			return err
+		}
+
+		jobID, err := cmd.Flags().GetString("job")
+		if err != nil {
+			return err
+		}
+
 		// build the plan for this run
 		var plan *model.Plan
-		if jobID, err := cmd.Flags().GetString("job"); err != nil {
-			return err
-		} else if jobID != "" {
+		if jobID != "" {
 			log.Debugf("Planning job: %s", jobID)
-			plan = planner.PlanJob(jobID)
+			plan, err = planner.PlanJob(jobID)
 		} else {
 			log.Debugf("Planning event: %s", eventName)
-			plan = planner.PlanEvent(eventName)
+			plan, err = planner.PlanEvent(eventName)
 		}
+		if err != nil {
+			return err
+		}
+		plan.FilterRuns(matrix)
 
 		// check if we should just print the graph
 		if list, err := cmd.Flags().GetBool("list"); err != nil {
@@ -96,6 +124,8 @@ func newRunCommand(ctx context.Context, input *Input) func(*cobra.Command, []str
 			ReuseContainers: input.reuseContainers,
 			Workdir:         input.Workdir(),
 			LogOutput:       input.logOutput,
+			Matrix:          matrix,
+			UsesCacheDir:    usesCacheDir,
 		}
 		runner, err := runner.New(config)
 		if err != nil {
@@ -106,21 +136,76 @@ func newRunCommand(ctx context.Context, input *Input) func(*cobra.Command, []str
 		if watch, err := cmd.Flags().GetBool("watch"); err != nil {
 			return err
 		} else if watch {
-			return watchAndRun(ctx, runner.NewPlanExecutor(plan))
+			debounce, err := cmd.Flags().GetDuration("watch-debounce")
+			if err != nil {
+				return err
+			}
+
+			replan := func() (*model.Plan, error) {
+				planner, err := model.NewWorkflowPlanner(input.WorkflowsPath(), input.Workdir(), usesCacheDir)
+				if err != nil {
+					return nil, err
+				}
+				var p *model.Plan
+				if jobID != "" {
+					p, err = planner.PlanJob(jobID)
+				} else {
+					p, err = planner.PlanEvent(eventName)
+				}
+				if err != nil {
+					return nil, err
+				}
+				p.FilterRuns(matrix)
+				return p, nil
+			}
+
+			execPlan := func(p *model.Plan) common.Executor {
+				return runner.NewPlanExecutor(p)
+			}
+
+			return watchAndRun(ctx, input.WorkflowsPath(), debounce, plan, replan, execPlan)
 		}
 
 		return runner.NewPlanExecutor(plan)(ctx)
 	}
 }
 
-func watchAndRun(ctx context.Context, fn common.Executor) error {
-	recurse := true
-	checkIntervalInSeconds := 2
+// parseMatrixFilters turns repeated `--matrix key=value` flags into the map of axis->value
+// constraints used to restrict which matrix combinations are run locally.
+func parseMatrixFilters(filters []string) (map[string]string, error) {
+	matrix := make(map[string]string, len(filters))
+	for _, filter := range filters {
+		parts := strings.SplitN(filter, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid matrix filter %q, expected key=value", filter)
+		}
+		matrix[parts[0]] = parts[1]
+	}
+	return matrix, nil
+}
+
+// watchAndRun watches the local repo for filesystem changes using fsnotify and re-runs the
+// plan whenever a burst of edits settles. Changes are coalesced within debounce so that a
+// single save (which editors often split into several write/rename events) only triggers one
+// run. The plan itself is only rebuilt via replan when a changed path falls under
+// workflowsPath; other changes just re-run the existing plan. A run still in flight when a new
+// change arrives is cancelled via its context rather than left to complete alongside the new
+// one.
+func watchAndRun(ctx context.Context, workflowsPath string, debounce time.Duration, plan *model.Plan, replan func() (*model.Plan, error), execPlan func(*model.Plan) common.Executor) error {
 	dir, err := os.Getwd()
 	if err != nil {
 		return err
 	}
 
+	// fsnotify events carry absolute paths (watchDirsRecursive walks from dir, itself
+	// absolute), but workflowsPath is the raw --workflows flag value and is typically
+	// relative (its default is "./.github/workflows/"). Resolve it once up front so the
+	// prefix comparison below actually matches.
+	absWorkflowsPath, err := filepath.Abs(workflowsPath)
+	if err != nil {
+		return err
+	}
+
 	var ignore *gitignore.GitIgnore
 	if _, err := os.Stat(filepath.Join(dir, ".gitignore")); !os.IsNotExist(err) {
 		ignore, _ = gitignore.CompileIgnoreFile(filepath.Join(dir, ".gitignore"))
@@ -128,31 +213,117 @@ func watchAndRun(ctx context.Context, fn common.Executor) error {
 		ignore = &gitignore.GitIgnore{}
 	}
 
-	folderWatcher := fswatch.NewFolderWatcher(
-		dir,
-		recurse,
-		ignore.MatchesPath,
-		checkIntervalInSeconds,
-	)
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
 
-	folderWatcher.Start()
+	if err := watchDirsRecursive(watcher, dir, ignore); err != nil {
+		return err
+	}
+
+	// pending, plan and runCancel are only ever touched from this goroutine: the debounce timer
+	// is drained through the select loop below rather than firing its own goroutine, so none of
+	// this needs a mutex.
+	var runCancel context.CancelFunc
+	pending := map[string]struct{}{}
+	var debounceTimer *time.Timer
 
-	go func() {
-		for folderWatcher.IsRunning() {
-			if err = fn(ctx); err != nil {
-				break
+	runPlan := func(p *model.Plan) {
+		if runCancel != nil {
+			runCancel()
+		}
+		runCtx, cancel := context.WithCancel(ctx)
+		runCancel = cancel
+		go func() {
+			if err := execPlan(p)(runCtx); err != nil && runCtx.Err() == nil {
+				log.Errorf("workflow run failed: %v", err)
 			}
-			log.Debugf("Watching %s for changes", dir)
-			for changes := range folderWatcher.ChangeDetails() {
-				log.Debugf("%s", changes.String())
-				if err = fn(ctx); err != nil {
+		}()
+	}
+
+	runPlan(plan)
+	log.Debugf("Watching %s for changes", dir)
+
+	for {
+		var debounceC <-chan time.Time
+		if debounceTimer != nil {
+			debounceC = debounceTimer.C
+		}
+
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				if runCancel != nil {
+					runCancel()
+				}
+				return nil
+			}
+			if ignore.MatchesPath(event.Name) {
+				continue
+			}
+			log.Debugf("%s", event)
+
+			pending[event.Name] = struct{}{}
+			if debounceTimer == nil {
+				debounceTimer = time.NewTimer(debounce)
+			} else {
+				debounceTimer.Reset(debounce)
+			}
+		case <-debounceC:
+			debounceTimer = nil
+			changed := pending
+			pending = map[string]struct{}{}
+
+			workflowsChanged := false
+			for path := range changed {
+				if strings.HasPrefix(path, absWorkflowsPath) {
+					workflowsChanged = true
 					break
 				}
-				log.Debugf("Watching %s for changes", dir)
 			}
+
+			if workflowsChanged {
+				log.Debugf("Workflow files changed, replanning")
+				newPlan, err := replan()
+				if err != nil {
+					log.Errorf("replanning failed: %v", err)
+					continue
+				}
+				plan = newPlan
+			}
+
+			runPlan(plan)
+			log.Debugf("Watching %s for changes", dir)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			log.Errorf("watch error: %v", err)
+		case <-ctx.Done():
+			if runCancel != nil {
+				runCancel()
+			}
+			return ctx.Err()
+		}
+	}
+}
+
+// watchDirsRecursive adds dir and all of its non-ignored subdirectories to watcher. fsnotify
+// only watches the directories it's explicitly told about, so new directories created after
+// watching has started won't be picked up until the next restart.
+func watchDirsRecursive(watcher *fsnotify.Watcher, dir string, ignore *gitignore.GitIgnore) error {
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if path != dir && ignore.MatchesPath(path) {
+			return filepath.SkipDir
 		}
-	}()
-	<-ctx.Done()
-	folderWatcher.Stop()
-	return err
+		return watcher.Add(path)
+	})
 }
@@ -0,0 +1,311 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/nektos/act/pkg/model"
+	"github.com/nektos/act/pkg/runner"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// serverCommand starts a long-running HTTP server that accepts webhook-shaped payloads and
+// dispatches them through the same runner.Config/NewPlanExecutor path the CLI uses, so `act`
+// can act as a lightweight self-hosted runner for local dev and CI mirrors.
+func serverCommand(ctx context.Context, input *Input) *cobra.Command {
+	var bindAddr string
+
+	cmd := &cobra.Command{
+		Use:   "server",
+		Short: "start an HTTP server that triggers runs from webhook-shaped payloads",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			usesCacheDir, err := cmd.Flags().GetString("uses-cache")
+			if err != nil {
+				return err
+			}
+
+			planner, err := model.NewWorkflowPlanner(input.WorkflowsPath(), input.Workdir(), usesCacheDir)
+			if err != nil {
+				return err
+			}
+
+			config := &runner.Config{
+				ForcePull:       input.forcePull,
+				ReuseContainers: input.reuseContainers,
+				Workdir:         input.Workdir(),
+				LogOutput:       input.logOutput,
+				UsesCacheDir:    usesCacheDir,
+			}
+			r, err := runner.New(config)
+			if err != nil {
+				return err
+			}
+
+			srv := newActServer(ctx, planner, r)
+			log.Infof("Listening on %s", bindAddr)
+			return http.ListenAndServe(bindAddr, srv.mux)
+		},
+	}
+
+	cmd.Flags().StringVar(&bindAddr, "bind", "127.0.0.1:8080", "address to bind the server to")
+	return cmd
+}
+
+// runStatus tracks an in-flight or completed plan execution so it can be listed and cancelled
+// by ID.
+type runStatus struct {
+	ID      string `json:"id"`
+	Event   string `json:"event"`
+	Running bool   `json:"running"`
+	Error   string `json:"error,omitempty"`
+	cancel  context.CancelFunc
+	logs    *logBroadcaster
+
+	// executing is true only while this run holds actServer.execMu and is actually inside
+	// runner.NewPlanExecutor, as opposed to merely queued with Running set. serverLogHook
+	// uses it, instead of Running, to decide which run a log line belongs to.
+	executing bool
+}
+
+type actServer struct {
+	ctx     context.Context
+	planner model.WorkflowPlanner
+	runner  runner.Runner
+	mux     *http.ServeMux
+
+	mu   sync.Mutex
+	runs map[string]*runStatus
+
+	// execMu serializes actual plan execution across runs. The runner logs through the
+	// package-global logrus logger, which carries no per-run identity, so serverLogHook can
+	// only attribute a log line correctly if at most one run is ever executing at a time.
+	execMu sync.Mutex
+}
+
+func newActServer(ctx context.Context, planner model.WorkflowPlanner, r runner.Runner) *actServer {
+	s := &actServer{
+		ctx:     ctx,
+		planner: planner,
+		runner:  r,
+		mux:     http.NewServeMux(),
+		runs:    map[string]*runStatus{},
+	}
+
+	s.mux.HandleFunc("/runs", s.handleTrigger)
+	s.mux.HandleFunc("/runs/list", s.handleList)
+	s.mux.HandleFunc("/runs/cancel", s.handleCancel)
+	s.mux.HandleFunc("/runs/logs", s.handleLogs)
+
+	log.AddHook(&serverLogHook{s: s})
+	return s
+}
+
+// serverLogHook forwards every log line emitted by the run currently executing to that run's
+// subscribers. It's a single global hook rather than one per run (logrus has no hook removal
+// API, so per-run hooks would leak for the server's lifetime), and the runner logs through the
+// shared global logger with no per-run identity of its own - so actServer.execMu keeps at most
+// one run "executing" at a time, making the lookup below unambiguous without needing to thread
+// a run ID through the runner package.
+type serverLogHook struct {
+	s *actServer
+}
+
+func (h *serverLogHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+func (h *serverLogHook) Fire(entry *log.Entry) error {
+	line, err := entry.String()
+	if err != nil {
+		return err
+	}
+	line = strings.TrimRight(line, "\n")
+
+	h.s.mu.Lock()
+	defer h.s.mu.Unlock()
+	for _, status := range h.s.runs {
+		if status.executing {
+			status.logs.publish(line)
+		}
+	}
+	return nil
+}
+
+// triggerRequest mirrors the fields act cares about from a GitHub webhook payload.
+type triggerRequest struct {
+	Event string `json:"event"` // push, pull_request, workflow_dispatch, ...
+	JobID string `json:"job,omitempty"`
+}
+
+func (s *actServer) handleTrigger(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req triggerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var plan *model.Plan
+	var err error
+	if req.JobID != "" {
+		plan, err = s.planner.PlanJob(req.JobID)
+	} else {
+		plan, err = s.planner.PlanEvent(req.Event)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	runCtx, cancel := context.WithCancel(s.ctx)
+	logs := newLogBroadcaster()
+
+	s.mu.Lock()
+	id := fmt.Sprintf("run-%d", len(s.runs)+1)
+	status := &runStatus{ID: id, Event: req.Event, Running: true, cancel: cancel, logs: logs}
+	s.runs[id] = status
+	resp := *status
+	s.mu.Unlock()
+
+	go func() {
+		s.execMu.Lock()
+		s.mu.Lock()
+		status.executing = true
+		s.mu.Unlock()
+
+		err := s.runner.NewPlanExecutor(plan)(runCtx)
+
+		s.mu.Lock()
+		status.executing = false
+		status.Running = false
+		if err != nil {
+			status.Error = err.Error()
+			logs.publish(fmt.Sprintf("error: %s", err))
+		}
+		s.mu.Unlock()
+		s.execMu.Unlock()
+		logs.close()
+	}()
+
+	// Encode the snapshot taken under s.mu above, not status itself: the goroutine above
+	// mutates status concurrently with this handler, and status is never read again here.
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (s *actServer) handleList(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	runs := make([]*runStatus, 0, len(s.runs))
+	for _, status := range s.runs {
+		runs = append(runs, status)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(runs)
+}
+
+func (s *actServer) handleCancel(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("id")
+
+	s.mu.Lock()
+	status, ok := s.runs[id]
+	s.mu.Unlock()
+
+	if !ok {
+		http.Error(w, "run not found", http.StatusNotFound)
+		return
+	}
+
+	status.cancel()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleLogs streams a run's log lines as Server-Sent Events until the run finishes or the
+// client disconnects.
+func (s *actServer) handleLogs(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("id")
+
+	s.mu.Lock()
+	status, ok := s.runs[id]
+	s.mu.Unlock()
+
+	if !ok {
+		http.Error(w, "run not found", http.StatusNotFound)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+
+	lines, done := status.logs.subscribe()
+	for {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				return
+			}
+			fmt.Fprintf(w, "data: %s\n\n", line)
+			flusher.Flush()
+		case <-done:
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// logBroadcaster fans a run's log lines out to any number of concurrent SSE subscribers.
+type logBroadcaster struct {
+	mu   sync.Mutex
+	subs []chan string
+	done chan struct{}
+}
+
+func newLogBroadcaster() *logBroadcaster {
+	return &logBroadcaster{done: make(chan struct{})}
+}
+
+func (b *logBroadcaster) subscribe() (<-chan string, <-chan struct{}) {
+	ch := make(chan string, 64)
+	b.mu.Lock()
+	b.subs = append(b.subs, ch)
+	b.mu.Unlock()
+	return ch, b.done
+}
+
+func (b *logBroadcaster) publish(line string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, ch := range b.subs {
+		select {
+		case ch <- line:
+		default:
+		}
+	}
+}
+
+func (b *logBroadcaster) close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	close(b.done)
+	for _, ch := range b.subs {
+		close(ch)
+	}
+}
@@ -0,0 +1,207 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MatrixCombination is a single expanded combination of a job's `strategy.matrix`,
+// e.g. {"os": "ubuntu-latest", "node": "14"}.
+type MatrixCombination map[string]interface{}
+
+// String renders a combination as a stable, comparable key such as `node=14,os=ubuntu-latest`.
+// It is used to match combinations against `--matrix key=value` CLI filters and to label
+// per-combination runs in `drawGraph`.
+func (m MatrixCombination) String() string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%v", k, m[k]))
+	}
+	return strings.Join(parts, ",")
+}
+
+// Strategy models a job's `strategy:` block. It has a custom UnmarshalYAML because `include`
+// and `exclude` are nested *inside* `matrix:` in the workflow schema, alongside the axes
+// themselves, and need to be split out from them.
+type Strategy struct {
+	FailFast bool
+	Matrix   map[string][]interface{}
+	Include  []MatrixCombination
+	Exclude  []MatrixCombination
+}
+
+// UnmarshalYAML splits a `strategy:` block's `matrix:` map into its axes (`Matrix`) and its
+// `include`/`exclude` lists, which GitHub Actions nests under the same `matrix:` key.
+func (s *Strategy) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var raw struct {
+		FailFast bool                   `yaml:"fail-fast"`
+		Matrix   map[string]interface{} `yaml:"matrix"`
+	}
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+
+	s.FailFast = raw.FailFast
+	s.Matrix = map[string][]interface{}{}
+
+	for axis, rawValues := range raw.Matrix {
+		values, ok := rawValues.([]interface{})
+		if !ok {
+			continue
+		}
+		switch axis {
+		case "include":
+			s.Include = toMatrixCombinations(values)
+		case "exclude":
+			s.Exclude = toMatrixCombinations(values)
+		default:
+			s.Matrix[axis] = values
+		}
+	}
+	return nil
+}
+
+// toMatrixCombinations converts the raw `[]interface{}` a YAML decoder produces for a list of
+// maps (e.g. `include:`/`exclude:` entries) into MatrixCombinations.
+func toMatrixCombinations(values []interface{}) []MatrixCombination {
+	combos := make([]MatrixCombination, 0, len(values))
+	for _, v := range values {
+		raw, ok := v.(map[interface{}]interface{})
+		if !ok {
+			continue
+		}
+		combo := make(MatrixCombination, len(raw))
+		for k, val := range raw {
+			combo[fmt.Sprintf("%v", k)] = val
+		}
+		combos = append(combos, combo)
+	}
+	return combos
+}
+
+// ExpandMatrix expands a `strategy.matrix` (including `include` and `exclude`) into the list of
+// combinations a job should run. A nil or empty strategy expands to a single, empty combination
+// so callers can treat matrix and non-matrix jobs uniformly.
+func ExpandMatrix(strategy *Strategy) []MatrixCombination {
+	if strategy == nil || len(strategy.Matrix) == 0 {
+		if strategy == nil || len(strategy.Include) == 0 {
+			return []MatrixCombination{{}}
+		}
+		// With no axes, each include entry is a combination of its own.
+		return append([]MatrixCombination(nil), strategy.Include...)
+	}
+
+	combinations := []MatrixCombination{{}}
+	for axis, values := range strategy.Matrix {
+		var expanded []MatrixCombination
+		for _, combo := range combinations {
+			for _, value := range values {
+				next := make(MatrixCombination, len(combo)+1)
+				for k, v := range combo {
+					next[k] = v
+				}
+				next[axis] = value
+				expanded = append(expanded, next)
+			}
+		}
+		combinations = expanded
+	}
+
+	combinations = excludeMatrixCombinations(combinations, strategy.Exclude)
+	combinations = includeMatrixCombinations(combinations, strategy.Include, strategy.Matrix)
+
+	return combinations
+}
+
+// includeMatrixCombinations applies `include` the way GitHub Actions does: an include entry
+// whose axis keys (keys also defined in the matrix itself) match an existing combination's
+// values is merged into that combination, adding any extra non-axis keys; an include entry
+// that matches no existing combination is added as a new combination of its own.
+func includeMatrixCombinations(combinations []MatrixCombination, include []MatrixCombination, axes map[string][]interface{}) []MatrixCombination {
+	for _, inc := range include {
+		matched := false
+		for i, combo := range combinations {
+			if !includeMatchesCombo(inc, combo, axes) {
+				continue
+			}
+			matched = true
+			merged := make(MatrixCombination, len(combo)+len(inc))
+			for k, v := range combo {
+				merged[k] = v
+			}
+			for k, v := range inc {
+				merged[k] = v
+			}
+			combinations[i] = merged
+		}
+		if !matched {
+			combinations = append(combinations, inc)
+		}
+	}
+	return combinations
+}
+
+// includeMatchesCombo reports whether inc's values agree with combo on every key inc shares
+// with axes (the matrix's own axes). Keys inc sets that aren't axes don't constrain the match,
+// since those are exactly the extra values include is meant to add.
+func includeMatchesCombo(inc, combo MatrixCombination, axes map[string][]interface{}) bool {
+	for k, v := range inc {
+		if _, isAxis := axes[k]; !isAxis {
+			continue
+		}
+		comboVal, ok := combo[k]
+		if !ok || fmt.Sprintf("%v", comboVal) != fmt.Sprintf("%v", v) {
+			return false
+		}
+	}
+	return true
+}
+
+func excludeMatrixCombinations(combinations []MatrixCombination, exclude []MatrixCombination) []MatrixCombination {
+	if len(exclude) == 0 {
+		return combinations
+	}
+
+	kept := make([]MatrixCombination, 0, len(combinations))
+	for _, combo := range combinations {
+		excluded := false
+		for _, ex := range exclude {
+			if matrixCombinationMatches(combo, ex) {
+				excluded = true
+				break
+			}
+		}
+		if !excluded {
+			kept = append(kept, combo)
+		}
+	}
+	return kept
+}
+
+func matrixCombinationMatches(combo, filter MatrixCombination) bool {
+	for k, v := range filter {
+		if comboVal, ok := combo[k]; !ok || fmt.Sprintf("%v", comboVal) != fmt.Sprintf("%v", v) {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchesMatrixFilters reports whether the combination satisfies every `--matrix key=value`
+// filter passed on the CLI. Filters on keys not present in the combination never match, so
+// passing an unknown axis excludes everything.
+func (m MatrixCombination) MatchesMatrixFilters(filters map[string]string) bool {
+	for k, v := range filters {
+		if fmt.Sprintf("%v", m[k]) != v {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,117 @@
+package model
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestExpandMatrix(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy *Strategy
+		want     []string
+	}{
+		{
+			name:     "nil strategy expands to a single empty combination",
+			strategy: nil,
+			want:     []string{""},
+		},
+		{
+			name:     "empty matrix expands to a single empty combination",
+			strategy: &Strategy{},
+			want:     []string{""},
+		},
+		{
+			name: "single axis",
+			strategy: &Strategy{
+				Matrix: map[string][]interface{}{"os": {"ubuntu-latest", "macos-latest"}},
+			},
+			want: []string{"os=macos-latest", "os=ubuntu-latest"},
+		},
+		{
+			name: "cartesian product of two axes",
+			strategy: &Strategy{
+				Matrix: map[string][]interface{}{
+					"os":   {"ubuntu-latest"},
+					"node": {12, 14},
+				},
+			},
+			want: []string{"node=12,os=ubuntu-latest", "node=14,os=ubuntu-latest"},
+		},
+		{
+			name: "exclude removes a matching combination",
+			strategy: &Strategy{
+				Matrix: map[string][]interface{}{
+					"os":   {"ubuntu-latest", "macos-latest"},
+					"node": {12, 14},
+				},
+				Exclude: []MatrixCombination{{"os": "macos-latest", "node": 12}},
+			},
+			want: []string{
+				"node=12,os=ubuntu-latest",
+				"node=14,os=macos-latest",
+				"node=14,os=ubuntu-latest",
+			},
+		},
+		{
+			name: "include merges into the existing combination it matches",
+			strategy: &Strategy{
+				Matrix:  map[string][]interface{}{"os": {"ubuntu-latest"}},
+				Include: []MatrixCombination{{"os": "ubuntu-latest", "node": 16}},
+			},
+			want: []string{"node=16,os=ubuntu-latest"},
+		},
+		{
+			name: "include is never dropped by an otherwise-matching exclude",
+			strategy: &Strategy{
+				Matrix:  map[string][]interface{}{"os": {"ubuntu-latest"}},
+				Include: []MatrixCombination{{"os": "ubuntu-latest", "node": 16}},
+				Exclude: []MatrixCombination{{"os": "ubuntu-latest"}},
+			},
+			want: []string{"node=16,os=ubuntu-latest"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			combos := ExpandMatrix(tt.strategy)
+			got := make([]string, len(combos))
+			for i, c := range combos {
+				got[i] = c.String()
+			}
+			sort.Strings(got)
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("ExpandMatrix() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("ExpandMatrix() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestMatrixCombinationMatchesMatrixFilters(t *testing.T) {
+	combo := MatrixCombination{"os": "ubuntu-latest", "node": 14}
+
+	tests := []struct {
+		name    string
+		filters map[string]string
+		want    bool
+	}{
+		{"no filters matches anything", nil, true},
+		{"matching filter", map[string]string{"os": "ubuntu-latest"}, true},
+		{"mismatched value", map[string]string{"os": "macos-latest"}, false},
+		{"unknown axis never matches", map[string]string{"arch": "arm64"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := combo.MatchesMatrixFilters(tt.filters); got != tt.want {
+				t.Errorf("MatchesMatrixFilters(%v) = %v, want %v", tt.filters, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package model
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Run is a single execution unit produced by planning: one job, in one workflow, for one
+// matrix combination (or the job's only combination, for jobs with no `strategy.matrix`).
+// A job that declares `uses:` never produces a Run itself - each job of the called workflow is
+// planned in its place (one Run per matrix combination, as above), with JobID of the form
+// "caller/called".
+type Run struct {
+	Workflow *Workflow
+	JobID    string
+	Job      *Job
+	Matrix   MatrixCombination
+}
+
+// Plan is the full set of runs produced by planning an event or a single job.
+type Plan struct {
+	Runs []*Run
+}
+
+// FilterRuns restricts the plan to runs whose matrix combination matches every constraint in
+// filters (from repeated `--matrix key=value` flags). Runs with no matrix are kept only when
+// filters is empty, since they have no axis to match against.
+func (p *Plan) FilterRuns(filters map[string]string) {
+	if len(filters) == 0 {
+		return
+	}
+
+	kept := make([]*Run, 0, len(p.Runs))
+	for _, run := range p.Runs {
+		if run.Matrix.MatchesMatrixFilters(filters) {
+			kept = append(kept, run)
+		}
+	}
+	p.Runs = kept
+}
+
+// WorkflowPlanner loads the workflow files under a directory and turns an event name or a
+// single job ID into a Plan.
+type WorkflowPlanner interface {
+	PlanEvent(eventName string) (*Plan, error)
+	PlanJob(jobID string) (*Plan, error)
+	GetEvents() []string
+}
+
+type workflowPlanner struct {
+	workflows []*Workflow
+	repoDir   string
+	cacheDir  string
+}
+
+// NewWorkflowPlanner loads every workflow file under workflowsPath. repoDir is the root a
+// local `uses:` reference is resolved against; cacheDir is where remote `uses:` references are
+// fetched and cached.
+func NewWorkflowPlanner(workflowsPath, repoDir, cacheDir string) (WorkflowPlanner, error) {
+	var matches []string
+	for _, pattern := range []string{"*.yml", "*.yaml"} {
+		found, err := filepath.Glob(filepath.Join(workflowsPath, pattern))
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, found...)
+	}
+
+	p := &workflowPlanner{repoDir: repoDir, cacheDir: cacheDir}
+	for _, match := range matches {
+		wf, err := parseWorkflow(match)
+		if err != nil {
+			return nil, err
+		}
+		p.workflows = append(p.workflows, wf)
+	}
+	return p, nil
+}
+
+func (p *workflowPlanner) GetEvents() []string {
+	seen := map[string]bool{}
+	var events []string
+	for _, wf := range p.workflows {
+		for _, e := range wf.Events() {
+			if !seen[e] {
+				seen[e] = true
+				events = append(events, e)
+			}
+		}
+	}
+	return events
+}
+
+// maxUsesDepth caps how many levels of `uses:` a reusable workflow chain may nest, matching
+// GitHub Actions' own limit. Without a cap, a workflow that (directly or transitively) uses:
+// itself would recurse forever and crash the planner instead of failing cleanly.
+const maxUsesDepth = 4
+
+func (p *workflowPlanner) PlanEvent(eventName string) (*Plan, error) {
+	plan := new(Plan)
+	for _, wf := range p.workflows {
+		if !wf.HasEvent(eventName) {
+			continue
+		}
+		for jobID, job := range wf.Jobs {
+			if err := p.addRuns(plan, wf, jobID, job, 0); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return plan, nil
+}
+
+func (p *workflowPlanner) PlanJob(jobID string) (*Plan, error) {
+	plan := new(Plan)
+	for _, wf := range p.workflows {
+		if job, ok := wf.Jobs[jobID]; ok {
+			if err := p.addRuns(plan, wf, jobID, job, 0); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return plan, nil
+}
+
+// addRuns expands job into one or more Runs. A job that declares `uses:` is resolved and
+// inlined via resolveUsesJob instead of being expanded directly. Otherwise job's
+// `strategy.matrix` (if any) is expanded into one Run per combination, injecting each
+// combination into the run's step env as MATRIX_<axis>. depth counts how many `uses:` levels
+// deep this job is, for resolveUsesJob's nesting check.
+func (p *workflowPlanner) addRuns(plan *Plan, wf *Workflow, jobID string, job *Job, depth int) error {
+	if job.Uses != "" {
+		return p.resolveUsesJob(plan, wf, jobID, job, depth)
+	}
+
+	for _, combo := range ExpandMatrix(job.Strategy) {
+		run := &Run{Workflow: wf, JobID: jobID, Job: job, Matrix: combo}
+		if len(combo) > 0 {
+			merged := *job
+			merged.Env = mergeEnv(job.Env, matrixEnv(combo))
+			run.Job = &merged
+		}
+		plan.Runs = append(plan.Runs, run)
+	}
+	return nil
+}
+
+// resolveUsesJob resolves job's `uses:` reference, parses the workflow it points at, and
+// inlines each of its jobs into plan as "callerJobID/calledJobID", with job's `with:` and
+// `secrets:` propagated into the called job's env. The called job's own `strategy.matrix` and
+// `uses:` (if any) are honoured recursively via addRuns, up to maxUsesDepth levels - beyond
+// that a workflow using: itself, directly or transitively, would recurse forever.
+func (p *workflowPlanner) resolveUsesJob(plan *Plan, callerWf *Workflow, jobID string, job *Job, depth int) error {
+	if depth >= maxUsesDepth {
+		return fmt.Errorf("job %q: uses: chain exceeds the maximum nesting depth of %d", jobID, maxUsesDepth)
+	}
+
+	ref, err := ParseUsesRef(job.Uses)
+	if err != nil {
+		return fmt.Errorf("job %q: %w", jobID, err)
+	}
+
+	path, err := ref.Resolve(p.repoDir, p.cacheDir)
+	if err != nil {
+		return fmt.Errorf("job %q: resolving %q: %w", jobID, job.Uses, err)
+	}
+
+	calledWf, err := parseWorkflow(path)
+	if err != nil {
+		return fmt.Errorf("job %q: %w", jobID, err)
+	}
+
+	propagated := usesEnv(job.With, job.Secrets)
+	for calledJobID, calledJob := range calledWf.Jobs {
+		merged := *calledJob
+		merged.Env = mergeEnv(calledJob.Env, propagated)
+		if err := p.addRuns(plan, calledWf, jobID+"/"+calledJobID, &merged, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// usesEnv turns a called job's `with:` and `secrets:` into the env a reusable workflow reads
+// its inputs and secrets from.
+func usesEnv(with, secrets map[string]string) map[string]string {
+	env := make(map[string]string, len(with)+len(secrets))
+	for k, v := range with {
+		env["INPUT_"+strings.ToUpper(k)] = v
+	}
+	for k, v := range secrets {
+		env["SECRET_"+strings.ToUpper(k)] = v
+	}
+	return env
+}
+
+func matrixEnv(combo MatrixCombination) map[string]string {
+	env := make(map[string]string, len(combo))
+	for k, v := range combo {
+		env["MATRIX_"+k] = fmt.Sprintf("%v", v)
+	}
+	return env
+}
+
+func mergeEnv(layers ...map[string]string) map[string]string {
+	env := map[string]string{}
+	for _, layer := range layers {
+		for k, v := range layer {
+			env[k] = v
+		}
+	}
+	return env
+}
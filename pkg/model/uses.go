@@ -0,0 +1,132 @@
+package model
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// UsesRef is a parsed `jobs.<id>.uses:` reference. It covers both the local form
+// (`./.github/workflows/build.yml`) and the remote form
+// (`owner/repo/.github/workflows/build.yml@ref`).
+type UsesRef struct {
+	Local bool
+	Owner string
+	Repo  string
+	Path  string
+	Ref   string
+}
+
+// ParseUsesRef parses a job's `uses:` string into a UsesRef. Local references start with
+// `./` and resolve relative to the directory passed to Resolve (the planner passes the
+// repository root); remote references are
+// `owner/repo/path/to/workflow.yml@ref`.
+func ParseUsesRef(uses string) (*UsesRef, error) {
+	if strings.HasPrefix(uses, "./") {
+		return &UsesRef{Local: true, Path: uses}, nil
+	}
+
+	atIdx := strings.LastIndex(uses, "@")
+	if atIdx == -1 {
+		return nil, fmt.Errorf("invalid reusable workflow reference %q: missing @ref", uses)
+	}
+	ref := uses[atIdx+1:]
+	rest := uses[:atIdx]
+
+	parts := strings.SplitN(rest, "/", 3)
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("invalid reusable workflow reference %q: expected owner/repo/path", uses)
+	}
+	owner, repo := parts[0], parts[1]
+	if !validUsesRefComponent(owner) {
+		return nil, fmt.Errorf("invalid reusable workflow reference %q: invalid owner %q", uses, owner)
+	}
+	if !validUsesRefComponent(repo) {
+		return nil, fmt.Errorf("invalid reusable workflow reference %q: invalid repo %q", uses, repo)
+	}
+
+	return &UsesRef{
+		Owner: owner,
+		Repo:  repo,
+		Path:  parts[2],
+		Ref:   ref,
+	}, nil
+}
+
+// validUsesRefComponent reports whether an owner or repo name is safe to use as a single path
+// segment when building the cache directory a remote reference is cloned into - i.e. it can't
+// introduce extra path segments or walk up out of that directory.
+func validUsesRefComponent(s string) bool {
+	return s != "" && s != "." && s != ".." && !strings.ContainsAny(s, "/\\")
+}
+
+// Resolve returns the local path to the workflow file the reference points at. Local
+// references resolve relative to callerDir. Remote references are fetched (shallow-cloned
+// at Ref) into cacheDir and cached there across calls, keyed by owner/repo@ref so repeated
+// runs don't re-fetch. In both cases the result is verified to stay under its root, since
+// u.Path comes straight from an author-controlled uses: string.
+func (u *UsesRef) Resolve(callerDir, cacheDir string) (string, error) {
+	if u.Local {
+		return resolveWithinRoot(callerDir, u.Path)
+	}
+
+	repoDir := filepath.Join(cacheDir, u.Owner, u.Repo+"@"+u.Ref)
+	if _, err := os.Stat(repoDir); os.IsNotExist(err) {
+		if err := fetchRemoteWorkflowRepo(u, repoDir); err != nil {
+			return "", err
+		}
+	}
+
+	return resolveWithinRoot(repoDir, u.Path)
+}
+
+// resolveWithinRoot joins root and path and rejects the result if it would land outside root,
+// e.g. a uses: reference like "owner/repo/../../../../etc/passwd@main".
+func resolveWithinRoot(root, path string) (string, error) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return "", err
+	}
+	resolved, err := filepath.Abs(filepath.Join(absRoot, path))
+	if err != nil {
+		return "", err
+	}
+	if resolved != absRoot && !strings.HasPrefix(resolved, absRoot+string(filepath.Separator)) {
+		return "", fmt.Errorf("uses: reference %q escapes %s", path, root)
+	}
+	return resolved, nil
+}
+
+// gitRefPattern matches a full 40-character commit SHA, the form `uses:` pinning commonly uses
+// for supply-chain security. `git clone --branch` only accepts branch/tag names, not arbitrary
+// commit SHAs, so those are fetched by cloning the default branch and checking out the SHA.
+var gitRefPattern = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+func fetchRemoteWorkflowRepo(u *UsesRef, dest string) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("https://github.com/%s/%s.git", u.Owner, u.Repo)
+
+	if gitRefPattern.MatchString(u.Ref) {
+		cloneCmd := exec.Command("git", "clone", url, dest)
+		if out, err := cloneCmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("cloning %s: %w: %s", url, err, out)
+		}
+		checkoutCmd := exec.Command("git", "-C", dest, "checkout", u.Ref)
+		if out, err := checkoutCmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("checking out %s@%s: %w: %s", url, u.Ref, err, out)
+		}
+		return nil
+	}
+
+	cmd := exec.Command("git", "clone", "--depth", "1", "--branch", u.Ref, url, dest)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("cloning %s@%s: %w: %s", url, u.Ref, err, out)
+	}
+	return nil
+}
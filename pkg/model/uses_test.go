@@ -0,0 +1,121 @@
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseUsesRef(t *testing.T) {
+	tests := []struct {
+		name    string
+		uses    string
+		want    *UsesRef
+		wantErr bool
+	}{
+		{
+			name: "local reference",
+			uses: "./.github/workflows/build.yml",
+			want: &UsesRef{Local: true, Path: "./.github/workflows/build.yml"},
+		},
+		{
+			name: "remote reference pinned to a tag",
+			uses: "octo-org/shared-workflows/.github/workflows/build.yml@v1",
+			want: &UsesRef{Owner: "octo-org", Repo: "shared-workflows", Path: ".github/workflows/build.yml", Ref: "v1"},
+		},
+		{
+			name: "remote reference pinned to a commit sha",
+			uses: "octo-org/shared-workflows/.github/workflows/build.yml@a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2",
+			want: &UsesRef{Owner: "octo-org", Repo: "shared-workflows", Path: ".github/workflows/build.yml", Ref: "a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2"},
+		},
+		{
+			name:    "remote reference missing @ref",
+			uses:    "octo-org/shared-workflows/.github/workflows/build.yml",
+			wantErr: true,
+		},
+		{
+			name:    "remote reference missing the path segment",
+			uses:    "octo-org/shared-workflows@v1",
+			wantErr: true,
+		},
+		{
+			name:    "owner attempting to escape the cache directory",
+			uses:    "../evil/shared-workflows/build.yml@v1",
+			wantErr: true,
+		},
+		{
+			name:    "repo attempting to escape the cache directory",
+			uses:    "octo-org/../evil/build.yml@v1",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseUsesRef(tt.uses)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseUsesRef(%q) = %+v, want error", tt.uses, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseUsesRef(%q) returned unexpected error: %v", tt.uses, err)
+			}
+			if *got != *tt.want {
+				t.Fatalf("ParseUsesRef(%q) = %+v, want %+v", tt.uses, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUsesRefResolveRejectsEscapingPaths(t *testing.T) {
+	root := t.TempDir()
+
+	tests := []struct {
+		name string
+		ref  *UsesRef
+	}{
+		{
+			name: "local path walks above the caller directory",
+			ref:  &UsesRef{Local: true, Path: "../../../../etc/passwd"},
+		},
+		{
+			name: "remote path walks above the resolved repo directory",
+			ref:  &UsesRef{Owner: "octo-org", Repo: "shared-workflows", Ref: "v1", Path: "../../../../etc/passwd"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.ref.Owner != "" {
+				// Avoid a real git clone: pre-create the cache dir the ref resolves to.
+				repoDir := filepath.Join(root, tt.ref.Owner, tt.ref.Repo+"@"+tt.ref.Ref)
+				if err := os.MkdirAll(repoDir, 0o755); err != nil {
+					t.Fatalf("setting up repo dir: %v", err)
+				}
+			}
+			if _, err := tt.ref.Resolve(root, root); err == nil {
+				t.Fatalf("Resolve(%+v) = nil error, want an error", tt.ref)
+			}
+		})
+	}
+}
+
+func TestGitRefPattern(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want bool
+	}{
+		{"a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2", true},
+		{"v1", false},
+		{"main", false},
+		{"a1b2c3d", false}, // short sha, not the full 40 chars git clone --branch would reject differently
+	}
+
+	for _, tt := range tests {
+		if got := gitRefPattern.MatchString(tt.ref); got != tt.want {
+			t.Errorf("gitRefPattern.MatchString(%q) = %v, want %v", tt.ref, got, tt.want)
+		}
+	}
+}
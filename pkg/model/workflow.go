@@ -0,0 +1,88 @@
+package model
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Workflow is the parsed form of a single workflow file under .github/workflows.
+type Workflow struct {
+	Name string          `yaml:"name"`
+	On   interface{}     `yaml:"on"`
+	Jobs map[string]*Job `yaml:"jobs"`
+}
+
+// Job is a single entry under a workflow's `jobs:`.
+type Job struct {
+	Name     string            `yaml:"name"`
+	RunsOn   interface{}       `yaml:"runs-on"`
+	Needs    []string          `yaml:"needs"`
+	If       string            `yaml:"if"`
+	Steps    []*Step           `yaml:"steps"`
+	Strategy *Strategy         `yaml:"strategy"`
+	Uses     string            `yaml:"uses"`
+	With     map[string]string `yaml:"with"`
+	Secrets  map[string]string `yaml:"secrets"`
+	Env      map[string]string `yaml:"env"`
+}
+
+// Step is a single entry under a job's `steps:`.
+type Step struct {
+	Name string            `yaml:"name"`
+	ID   string            `yaml:"id"`
+	Uses string            `yaml:"uses"`
+	Run  string            `yaml:"run"`
+	With map[string]string `yaml:"with"`
+	Env  map[string]string `yaml:"env"`
+}
+
+// parseWorkflow reads and unmarshals a single workflow YAML file.
+func parseWorkflow(path string) (*Workflow, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	w := new(Workflow)
+	if err := yaml.Unmarshal(data, w); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return w, nil
+}
+
+// Events returns the event names a workflow's `on:` triggers on, covering the string, list and
+// map forms GitHub Actions accepts.
+func (w *Workflow) Events() []string {
+	switch on := w.On.(type) {
+	case string:
+		return []string{on}
+	case []interface{}:
+		events := make([]string, 0, len(on))
+		for _, e := range on {
+			events = append(events, fmt.Sprintf("%v", e))
+		}
+		return events
+	case map[interface{}]interface{}:
+		events := make([]string, 0, len(on))
+		for k := range on {
+			events = append(events, fmt.Sprintf("%v", k))
+		}
+		sort.Strings(events)
+		return events
+	default:
+		return nil
+	}
+}
+
+// HasEvent reports whether the workflow triggers on the named event.
+func (w *Workflow) HasEvent(name string) bool {
+	for _, e := range w.Events() {
+		if e == name {
+			return true
+		}
+	}
+	return false
+}